@@ -31,6 +31,8 @@ func New(app types.App) http.Handler {
 	api.Handle("/config", apiHandler(api.config))
 	apiSubs := api.PathPrefix("/subtitles").Subrouter()
 	api.subtitleRouter(apiSubs)
+	apiActions := api.PathPrefix("/actions").Subrouter()
+	api.actionsRouter(apiActions)
 
 	return api
 }
@@ -0,0 +1,374 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/spf13/viper"
+	"github.com/tympanix/supper/media"
+	"github.com/tympanix/supper/provider"
+	"github.com/tympanix/supper/types"
+)
+
+// actionEvent is a single progress update emitted while an action runs.
+type actionEvent struct {
+	Type   string `json:"type"` // started, scraped, renamed, skipped, error
+	Path   string `json:"path"`
+	Reason string `json:"reason,omitempty"` // set on "skipped", e.g. "exists" or "cam"
+	Error  string `json:"error,omitempty"`
+}
+
+// job tracks the progress of a long-running action (rename or scrape) so it
+// can be observed over SSE by GET /actions/{id}/events.
+type job struct {
+	ID     string
+	Path   string
+	Kind   string
+	cancel chan struct{}
+
+	mu         sync.Mutex
+	cancelOnce sync.Once
+	events     []actionEvent
+	subs       map[chan actionEvent]struct{}
+	done       bool
+}
+
+func newActionID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func newJob(path, kind string) *job {
+	return &job{
+		ID:     newActionID(),
+		Path:   path,
+		Kind:   kind,
+		cancel: make(chan struct{}),
+		subs:   make(map[chan actionEvent]struct{}),
+	}
+}
+
+func (j *job) emit(e actionEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.events = append(j.events, e)
+	for sub := range j.subs {
+		select {
+		case sub <- e:
+		default:
+		}
+	}
+}
+
+func (j *job) finish() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.done = true
+	for sub := range j.subs {
+		close(sub)
+	}
+	j.subs = nil
+}
+
+func (j *job) abort() {
+	j.cancelOnce.Do(func() {
+		close(j.cancel)
+	})
+}
+
+// subscribe returns a channel receiving the events emitted after the call
+// returns, replaying everything already emitted first, so a client
+// connecting to GET /actions/{id}/events after the job has made progress
+// (or already finished) still sees the history instead of only whatever
+// fires from here on. An event emitted while the channel's buffer is full
+// is dropped for that subscriber.
+func (j *job) subscribe() chan actionEvent {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	ch := make(chan actionEvent, len(j.events)+16)
+	for _, e := range j.events {
+		ch <- e
+	}
+	if j.done {
+		close(ch)
+		return ch
+	}
+	j.subs[ch] = struct{}{}
+	return ch
+}
+
+func (j *job) unsubscribe(ch chan actionEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	delete(j.subs, ch)
+}
+
+// jobs is the in-memory registry of actions triggered via the API.
+var jobs = struct {
+	mu   sync.Mutex
+	byID map[string]*job
+}{byID: make(map[string]*job)}
+
+func addJob(j *job) {
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+	jobs.byID[j.ID] = j
+}
+
+func getJob(id string) (*job, bool) {
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+	j, ok := jobs.byID[id]
+	return j, ok
+}
+
+func removeJob(id string) {
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+	delete(jobs.byID, id)
+}
+
+func listJobs() []*job {
+	jobs.mu.Lock()
+	defer jobs.mu.Unlock()
+	list := make([]*job, 0, len(jobs.byID))
+	for _, j := range jobs.byID {
+		list = append(list, j)
+	}
+	return list
+}
+
+type actionRequest struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+	Force  bool   `json:"force"`
+	DryRun bool   `json:"dry_run"`
+}
+
+func (a *API) actionsRouter(r *mux.Router) {
+	r.Handle("", apiHandler(a.listActions)).Methods("GET")
+	r.Handle("/rename", apiHandler(a.postRename)).Methods("POST")
+	r.Handle("/scrape", apiHandler(a.postScrape)).Methods("POST")
+	r.Handle("/{id}", apiHandler(a.deleteAction)).Methods("DELETE")
+	r.HandleFunc("/{id}/events", a.actionEvents).Methods("GET")
+}
+
+// jsonAction is the representation of a job returned by GET /actions, so a
+// client can tell which job is which and whether it has finished without
+// separately opening GET /actions/{id}/events for every ID.
+type jsonAction struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	Done bool   `json:"done"`
+}
+
+func (a *API) listActions(w http.ResponseWriter, r *http.Request) interface{} {
+	list := listJobs()
+	actions := make([]jsonAction, len(list))
+	for i, j := range list {
+		j.mu.Lock()
+		actions[i] = jsonAction{
+			ID:   j.ID,
+			Path: j.Path,
+			Kind: j.Kind,
+			Done: j.done,
+		}
+		j.mu.Unlock()
+	}
+	return struct {
+		Actions []jsonAction `json:"actions"`
+	}{actions}
+}
+
+func (a *API) postRename(w http.ResponseWriter, r *http.Request) interface{} {
+	var req actionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return Error(err, http.StatusBadRequest)
+	}
+	if req.Path == "" {
+		return Error(errors.New("missing path"), http.StatusBadRequest)
+	}
+	if req.Action == "" {
+		req.Action = "copy"
+	}
+
+	j := newJob(req.Path, "rename")
+	addJob(j)
+	go a.renameJob(j, req)
+
+	return struct {
+		ID string `json:"id"`
+	}{j.ID}
+}
+
+func (a *API) postScrape(w http.ResponseWriter, r *http.Request) interface{} {
+	var req actionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return Error(err, http.StatusBadRequest)
+	}
+	if req.Path == "" {
+		return Error(errors.New("missing path"), http.StatusBadRequest)
+	}
+
+	j := newJob(req.Path, "scrape")
+	addJob(j)
+	go a.scrapeJob(j, req)
+
+	return struct {
+		ID string `json:"id"`
+	}{j.ID}
+}
+
+func (a *API) deleteAction(w http.ResponseWriter, r *http.Request) interface{} {
+	id := mux.Vars(r)["id"]
+	j, ok := getJob(id)
+	if !ok {
+		return Error(errors.New("unknown action"), http.StatusNotFound)
+	}
+	j.abort()
+	removeJob(id)
+	return struct {
+		ID string `json:"id"`
+	}{id}
+}
+
+func (a *API) actionEvents(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	j, ok := getJob(id)
+	if !ok {
+		http.Error(w, "unknown action", http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	sub := j.subscribe()
+	defer j.unsubscribe(sub)
+
+	for {
+		select {
+		case e, ok := <-sub:
+			if !ok {
+				return
+			}
+			b, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", b)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// renameJob runs a rename action, emitting a progress event per media file
+// found below req.Path.
+func (a *API) renameJob(j *job, req actionRequest) {
+	defer j.finish()
+
+	opts := types.RenameOptions{
+		Action:    req.Action,
+		Force:     req.Force,
+		DryRun:    req.DryRun,
+		RejectCam: viper.GetBool("reject_cam"),
+	}
+
+	list, err := a.FindMedia(req.Path)
+	if err != nil {
+		j.emit(actionEvent{Type: "error", Error: err.Error()})
+		return
+	}
+
+	for _, m := range list {
+		select {
+		case <-j.cancel:
+			return
+		default:
+		}
+
+		j.emit(actionEvent{Type: "started", Path: m.Name()})
+
+		itemOpts := opts
+		skipped := false
+		itemOpts.OnSkip = func(path, reason string) {
+			skipped = true
+			j.emit(actionEvent{Type: "skipped", Path: path, Reason: reason})
+		}
+
+		if err := a.RenameMedia(media.NewLocalMediaList([]types.LocalMedia{m}), itemOpts); err != nil {
+			j.emit(actionEvent{Type: "error", Path: m.Name(), Error: err.Error()})
+			continue
+		}
+
+		if !skipped {
+			j.emit(actionEvent{Type: "renamed", Path: m.Name()})
+		}
+	}
+}
+
+// scrapeJob runs a metadata-only refresh, emitting a progress event per
+// media file found below req.Path.
+func (a *API) scrapeJob(j *job, req actionRequest) {
+	defer j.finish()
+
+	list, err := a.FindMedia(req.Path)
+	if err != nil {
+		j.emit(actionEvent{Type: "error", Error: err.Error()})
+		return
+	}
+
+	for _, m := range list {
+		select {
+		case <-j.cancel:
+			return
+		default:
+		}
+
+		j.emit(actionEvent{Type: "started", Path: m.Name()})
+
+		scraped, err := scrapeWith(a.Scrapers(), m)
+		if err != nil {
+			j.emit(actionEvent{Type: "error", Path: m.Name(), Error: err.Error()})
+			continue
+		}
+
+		if err := m.Merge(scraped); err != nil {
+			j.emit(actionEvent{Type: "error", Path: m.Name(), Error: err.Error()})
+			continue
+		}
+
+		j.emit(actionEvent{Type: "scraped", Path: m.Name()})
+	}
+}
+
+func scrapeWith(scrapers []types.Scraper, m types.Media) (types.Media, error) {
+	for _, s := range scrapers {
+		scraped, err := s.Scrape(m)
+		if err != nil {
+			if provider.IsErrMediaNotSupported(err) {
+				continue
+			}
+			return nil, err
+		}
+		return scraped, nil
+	}
+	return nil, errors.New("no scrapers to use for media")
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/tympanix/supper/media"
+	"github.com/tympanix/supper/types"
+)
+
+func (a *API) subtitleRouter(r *mux.Router) {
+	r.Handle("", apiHandler(a.subtitles)).Methods("GET")
+}
+
+// subtitles lists the local subtitles found next to the media at the path
+// given by the "path" query parameter. Hearing-impaired subtitles are left
+// out by default in favor of regular ones, unless explicitly requested with
+// ?hi=true.
+func (a *API) subtitles(w http.ResponseWriter, r *http.Request) interface{} {
+	v := r.URL.Query()
+
+	dir := v.Get("path")
+	if dir == "" {
+		return Error(errors.New("missing path"), http.StatusBadRequest)
+	}
+
+	langs, err := a.queryLang(r)
+	if err != nil {
+		return Error(err, http.StatusBadRequest)
+	}
+
+	hi := v.Get("hi") == "true"
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return Error(err, http.StatusBadRequest)
+	}
+
+	list := make([]types.Subtitle, 0)
+	for _, f := range files {
+		sub, err := media.NewLocalSubtitle(f)
+		if err != nil {
+			continue
+		}
+		if !langs.Has(sub.Language()) {
+			continue
+		}
+		if sub.IsHI() != hi {
+			continue
+		}
+		list = append(list, sub)
+	}
+
+	return list
+}
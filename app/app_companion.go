@@ -0,0 +1,148 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/spf13/viper"
+	"github.com/tympanix/supper/types"
+)
+
+// defaultCompanionExts are the file extensions considered companions to a
+// media file when the "companions" config key is not set.
+var defaultCompanionExts = []string{
+	"srt", "ass", "ssa", "sub", "idx", "vtt", "nfo", "jpg", "png",
+}
+
+// CompanionFinder locates the companion files belonging to a piece of
+// local media, such as subtitles, nfo files and artwork, so renamers can
+// move them alongside the media itself instead of orphaning them in the
+// source directory.
+type CompanionFinder interface {
+	Companions(local types.Local) ([]string, error)
+}
+
+type companionFinderFunc func(types.Local) ([]string, error)
+
+func (f companionFinderFunc) Companions(local types.Local) ([]string, error) {
+	return f(local)
+}
+
+// Companions is the default CompanionFinder used by the renamers. It looks
+// for files in the same directory as local which share its basename, either
+// dot-separated, e.g. "Movie.en.srt" or "Movie.nfo" next to "Movie.mkv", or
+// dash-separated as is common for artwork, e.g. "Movie-poster.jpg".
+var Companions CompanionFinder = companionFinderFunc(findCompanions)
+
+func companionExts() map[string]struct{} {
+	exts := viper.GetStringSlice("companions")
+	if len(exts) == 0 {
+		exts = defaultCompanionExts
+	}
+	set := make(map[string]struct{}, len(exts))
+	for _, ext := range exts {
+		set[strings.ToLower(strings.TrimPrefix(ext, "."))] = struct{}{}
+	}
+	return set
+}
+
+func findCompanions(local types.Local) ([]string, error) {
+	mpath, ok := local.(types.Pather)
+	if !ok {
+		return nil, nil
+	}
+
+	dir := filepath.Dir(mpath.Path())
+	name := filepath.Base(mpath.Path())
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	exts := companionExts()
+	companions := make([]string, 0)
+
+	for _, f := range files {
+		if f.IsDir() || f.Name() == name {
+			continue
+		}
+		if !strings.HasPrefix(f.Name(), base+".") && !strings.HasPrefix(f.Name(), base+"-") {
+			continue
+		}
+		ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(f.Name()), "."))
+		if _, ok := exts[ext]; !ok {
+			continue
+		}
+		companions = append(companions, filepath.Join(dir, f.Name()))
+	}
+
+	return companions, nil
+}
+
+// companionFileOp performs the same kind of operation as action (copy,
+// move, symlink or hardlink) on a plain source/destination path pair,
+// mirroring the renamer of the same name but without requiring a
+// types.Local.
+func companionFileOp(action, src, dest string) error {
+	switch action {
+	case "copy":
+		in, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.Create(dest)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		_, err = io.Copy(out, in)
+		return err
+	case "move":
+		return os.Rename(src, dest)
+	case "symlink":
+		return os.Symlink(src, dest)
+	case "hardlink":
+		return os.Link(src, dest)
+	default:
+		return fmt.Errorf("%s: unknown action", action)
+	}
+}
+
+// renameCompanions renames the companion files belonging to local alongside
+// it, preserving the suffix distinguishing each companion from the media
+// itself, e.g. the ".en.forced.srt" part of "Movie.en.forced.srt".
+func renameCompanions(local types.Local, dest string, action string) error {
+	mpath, ok := local.(types.Pather)
+	if !ok {
+		return nil
+	}
+
+	companions, err := Companions.Companions(local)
+	if err != nil || len(companions) == 0 {
+		return err
+	}
+
+	name := filepath.Base(mpath.Path())
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	destBase := strings.TrimSuffix(dest, filepath.Ext(dest))
+
+	for _, companion := range companions {
+		suffix := strings.TrimPrefix(filepath.Base(companion), base)
+		companionDest := destBase + suffix
+		if err := companionFileOp(action, companion, companionDest); err != nil {
+			log.WithField("path", companionDest).WithError(err).Warn("Failed to rename companion file")
+			continue
+		}
+		log.WithField("path", companionDest).Debug("Companion file renamed")
+	}
+
+	return nil
+}
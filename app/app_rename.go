@@ -1,7 +1,6 @@
 package app
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -10,7 +9,6 @@ import (
 	"regexp"
 
 	"github.com/apex/log"
-	"github.com/spf13/viper"
 	"github.com/tympanix/supper/provider"
 	"github.com/tympanix/supper/types"
 )
@@ -21,10 +19,16 @@ func (s *mediaExistsError) Error() string {
 	return "media allready exists"
 }
 
+type camRejectedError struct{}
+
+func (s *camRejectedError) Error() string {
+	return "media is a cam release"
+}
+
 type renamer func(types.Local, string) error
 
 // Rename is a wrapper function around a renamer which performs some sanity checks
-func (r renamer) Rename(local types.Local, dest string, force bool) error {
+func (r renamer) Rename(local types.Local, dest string, action string, force bool) error {
 	_, err := os.Stat(dest)
 	if !force && err == nil {
 		return &mediaExistsError{}
@@ -35,7 +39,10 @@ func (r renamer) Rename(local types.Local, dest string, force bool) error {
 		}
 		log.WithField("path", dest).Debug("Removed existing media")
 	}
-	return r(local, dest)
+	if err := r(local, dest); err != nil {
+		return err
+	}
+	return renameCompanions(local, dest, action)
 }
 
 func copyRenamer(local types.Local, dest string) error {
@@ -130,16 +137,17 @@ func truncateSpaces(str string) string {
 }
 
 // RenameMedia traverses the local media list and renames the media
-func (a *Application) RenameMedia(list types.LocalMediaList) error {
+// according to opts.
+func (a *Application) RenameMedia(list types.LocalMediaList, opts types.RenameOptions) error {
 
-	doRename, ok := Renamers[viper.GetString("action")]
+	doRename, ok := Renamers[opts.Action]
 
 	if !ok {
-		return fmt.Errorf("%s: unknown action", viper.GetString("action"))
+		return fmt.Errorf("%s: unknown action", opts.Action)
 	}
 
 	for _, m := range list.List() {
-		ctx := log.WithField("media", m).WithField("action", viper.GetString("action"))
+		ctx := log.WithField("media", m).WithField("action", opts.Action)
 
 		scraped, err := a.scrapeMedia(m)
 
@@ -151,10 +159,12 @@ func (a *Application) RenameMedia(list types.LocalMediaList) error {
 			return err
 		}
 
-		if movie, ok := m.TypeMovie(); ok {
-			err = a.renameMovie(m, movie, doRename)
+		if opts.RejectCam && isCam(m.Meta()) {
+			err = &camRejectedError{}
+		} else if movie, ok := m.TypeMovie(); ok {
+			err = a.renameMovie(m, movie, doRename, opts)
 		} else if episode, ok := m.TypeEpisode(); ok {
-			err = a.renameEpisode(m, episode, doRename)
+			err = a.renameEpisode(m, episode, doRename, opts)
 		} else {
 			err = errors.New("unknown media format cannot rename")
 		}
@@ -166,6 +176,14 @@ func (a *Application) RenameMedia(list types.LocalMediaList) error {
 		if err != nil {
 			if _, ok := err.(*mediaExistsError); ok {
 				ctx.WithField("reason", "media already exists").Warn("Rename skipped")
+				if opts.OnSkip != nil {
+					opts.OnSkip(m.Name(), "exists")
+				}
+			} else if _, ok := err.(*camRejectedError); ok {
+				ctx.WithField("reason", "cam release rejected").Warn("Rename skipped")
+				if opts.OnSkip != nil {
+					opts.OnSkip(m.Name(), "cam")
+				}
 			} else {
 				ctx.WithError(err).Error("Rename failed")
 			}
@@ -191,60 +209,135 @@ func (a *Application) scrapeMedia(m types.Media) (types.Media, error) {
 	return nil, errors.New("no scrapers to use for media")
 }
 
-func (a *Application) renameMovie(local types.Local, m types.Movie, rename renamer) error {
-	var buf bytes.Buffer
+// camDetector is implemented by concrete metadata which can tell whether
+// the release is a cam/telesync recording, such as media.Metadata. It is
+// checked via type assertion rather than added to types.Metadata itself so
+// this stays a duck-typed extension, like imdbIDer/tmdbIDer/languager in
+// app_template.go.
+type camDetector interface {
+	IsCam() bool
+}
+
+// isCam reports whether meta identifies a cam/telesync release, or false
+// if meta is nil or doesn't implement camDetector.
+func isCam(meta types.Metadata) bool {
+	if meta == nil {
+		return false
+	}
+	d, ok := meta.(camDetector)
+	return ok && d.IsCam()
+}
+
+// audioChanneler is implemented by concrete metadata which expose an audio
+// channel layout, such as media.Metadata. It is checked via type assertion
+// rather than added to types.Metadata itself, for the same reason as
+// camDetector above.
+type audioChanneler interface {
+	AudioChannels() string
+}
+
+// audioChannelsOf returns the audio channel layout of m's metadata, e.g.
+// "5.1", or the empty string if m has no metadata or its metadata doesn't
+// expose one.
+func audioChannelsOf(m types.Media) string {
+	meta := m.Meta()
+	if meta == nil {
+		return ""
+	}
+	if a, ok := meta.(audioChanneler); ok {
+		return a.AudioChannels()
+	}
+	return ""
+}
+
+func (a *Application) renameMovie(local types.Local, m types.Movie, rename renamer, opts types.RenameOptions) error {
 	template := a.Config().Movies().Template()
 	if template == nil {
 		return errors.New("missing template for movies")
 	}
 	data := struct {
-		Movie   string
-		Year    int
-		Quality string
-		Codec   string
-		Group   string
+		Movie         string
+		Year          int
+		ReleaseYear   int
+		Quality       string
+		Resolution    string
+		Source        string
+		Codec         string
+		Group         string
+		Language      string
+		AudioChannels string
+		IMDbID        string
+		TMDbID        string
 	}{
-		Movie:   cleanString(m.MovieName()),
-		Year:    m.Year(),
-		Quality: m.Quality().String(),
-		Codec:   m.Codec().String(),
-		Group:   cleanString(m.Group()),
+		Movie:         cleanString(m.MovieName()),
+		Year:          m.Year(),
+		ReleaseYear:   m.Year(),
+		Quality:       m.Quality().String(),
+		Resolution:    m.Quality().String(),
+		Source:        m.Source().String(),
+		Codec:         m.Codec().String(),
+		Group:         cleanString(m.Group()),
+		Language:      languageOf(m),
+		AudioChannels: audioChannelsOf(m),
+		IMDbID:        imdbID(m),
+		TMDbID:        tmdbID(m),
 	}
-	if err := template.Execute(&buf, &data); err != nil {
+	dest, err := buildPath(a.Config().Movies().Directory(), template, &data)
+	if err != nil {
 		return err
 	}
-	filename := truncateSpaces(buf.String() + filepath.Ext(local.Name()))
-	dest := filepath.Join(a.Config().Movies().Directory(), filename)
-	return rename.Rename(local, dest, a.Config().Force())
+	dest = dest + filepath.Ext(local.Name())
+	if opts.DryRun {
+		log.WithField("media", local).WithField("dest", dest).Info("Dry run: media would be renamed")
+		return nil
+	}
+	return rename.Rename(local, dest, opts.Action, opts.Force)
 }
 
-func (a *Application) renameEpisode(local types.Local, e types.Episode, rename renamer) error {
-	var buf bytes.Buffer
+func (a *Application) renameEpisode(local types.Local, e types.Episode, rename renamer, opts types.RenameOptions) error {
 	template := a.Config().TVShows().Template()
 	if template == nil {
 		return errors.New("missing template for tvshows")
 	}
 	data := struct {
-		TVShow  string
-		Name    string
-		Episode int
-		Season  int
-		Quality string
-		Codec   string
-		Group   string
+		TVShow        string
+		Name          string
+		Episode       int
+		Episodes      string
+		Season        int
+		Quality       string
+		Resolution    string
+		Source        string
+		Codec         string
+		Group         string
+		Language      string
+		AudioChannels string
+		IMDbID        string
+		TMDbID        string
 	}{
-		TVShow:  cleanString(e.TVShow()),
-		Name:    cleanString(e.EpisodeName()),
-		Episode: e.Episode(),
-		Season:  e.Season(),
-		Quality: e.Quality().String(),
-		Codec:   e.Codec().String(),
-		Group:   cleanString(e.Group()),
-	}
-	if err := template.Execute(&buf, &data); err != nil {
+		TVShow:        cleanString(e.TVShow()),
+		Name:          cleanString(e.EpisodeName()),
+		Episode:       e.Episode(),
+		Episodes:      episodeRange(e),
+		Season:        e.Season(),
+		Quality:       e.Quality().String(),
+		Resolution:    e.Quality().String(),
+		Source:        e.Source().String(),
+		Codec:         e.Codec().String(),
+		Group:         cleanString(e.Group()),
+		Language:      languageOf(e),
+		AudioChannels: audioChannelsOf(e),
+		IMDbID:        imdbID(e),
+		TMDbID:        tmdbID(e),
+	}
+	dest, err := buildPath(a.Config().TVShows().Directory(), template, &data)
+	if err != nil {
 		return err
 	}
-	filename := truncateSpaces(buf.String() + filepath.Ext(local.Name()))
-	dest := filepath.Join(a.Config().TVShows().Directory(), filename)
-	return rename.Rename(local, dest, a.Config().Force())
+	dest = dest + filepath.Ext(local.Name())
+	if opts.DryRun {
+		log.WithField("media", local).WithField("dest", dest).Info("Dry run: media would be renamed")
+		return nil
+	}
+	return rename.Rename(local, dest, opts.Action, opts.Force)
 }
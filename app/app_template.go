@@ -0,0 +1,96 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/tympanix/supper/types"
+)
+
+// buildPath executes tpl and turns the result into a path rooted at base.
+// The template may emit forward slashes to describe nested directories,
+// e.g. "{{.TVShow}}/Season {{pad 2 .Season}}"; each segment is cleaned and
+// trimmed independently before being joined using the OS path separator.
+// Token values are substituted before the split, so a "/" inside a token
+// also starts a new segment; renameMovie and renameEpisode pass names
+// through cleanString first, which removes it.
+//
+// The "pad" function and any other path template funcs are registered by
+// types.TemplateFuncs where the templates are parsed (in config), not here.
+func buildPath(base string, tpl types.PathTemplate, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	segments := strings.Split(buf.String(), "/")
+	clean := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		seg = truncateSpaces(strings.TrimSpace(cleanString(seg)))
+		if seg == "" {
+			continue
+		}
+		clean = append(clean, seg)
+	}
+
+	return filepath.Join(append([]string{base}, clean...)...), nil
+}
+
+// imdbIDer is implemented by media which expose an IMDb identifier.
+type imdbIDer interface {
+	IMDbID() string
+}
+
+// tmdbIDer is implemented by media which expose a TMDb identifier.
+type tmdbIDer interface {
+	TMDbID() string
+}
+
+// languager is implemented by media which expose their audio language.
+type languager interface {
+	Language() string
+}
+
+func imdbID(v interface{}) string {
+	if i, ok := v.(imdbIDer); ok {
+		return i.IMDbID()
+	}
+	return ""
+}
+
+func tmdbID(v interface{}) string {
+	if t, ok := v.(tmdbIDer); ok {
+		return t.TMDbID()
+	}
+	return ""
+}
+
+func languageOf(v interface{}) string {
+	if l, ok := v.(languager); ok {
+		return l.Language()
+	}
+	return ""
+}
+
+// multiEpisoder is implemented by episodes spanning more than one episode
+// number, e.g. a release covering "S01E02E03".
+type multiEpisoder interface {
+	Episodes() []int
+}
+
+// episodeRange formats e's episode number for use in filenames, rendering
+// multi-episode releases as e.g. "E02-E03" instead of just the first one.
+func episodeRange(e types.Episode) string {
+	if me, ok := e.(multiEpisoder); ok {
+		if eps := me.Episodes(); len(eps) > 1 {
+			parts := make([]string, len(eps))
+			for i, ep := range eps {
+				parts[i] = fmt.Sprintf("E%02d", ep)
+			}
+			return strings.Join(parts, "-")
+		}
+	}
+	return fmt.Sprintf("E%02d", e.Episode())
+}
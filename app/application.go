@@ -33,7 +33,7 @@ func (a *Application) FindMedia(root string) ([]types.LocalMedia, error) {
 		}
 		for _, ext := range filetypes {
 			if ext == path.Ext(filepath) {
-				_media, err := media.New(f)
+				_media, err := media.New(filepath, f)
 				if err != nil {
 					return fmt.Errorf("Cound not parse file: %s", filepath)
 				}
@@ -0,0 +1,237 @@
+package media
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tympanix/supper/parse"
+	"github.com/tympanix/supper/types"
+)
+
+// localMediaList is a fixed, in-memory types.LocalMediaList over a slice of
+// already-resolved local media.
+type localMediaList []types.LocalMedia
+
+// NewLocalMediaList returns a types.LocalMediaList backed by list, e.g. for
+// handing a single file found outside of Application.FindMedia's own walk
+// to Application.RenameMedia.
+func NewLocalMediaList(list []types.LocalMedia) types.LocalMediaList {
+	return localMediaList(list)
+}
+
+func (l localMediaList) List() []types.LocalMedia {
+	return l
+}
+
+// New parses a local media file found at path into the appropriate concrete
+// type (movie or episode), using parse.Filename to interpret its release
+// name.
+func New(path string, file os.FileInfo) (types.LocalMedia, error) {
+	name := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+	parsed := parse.Filename(name)
+	meta := ParseMetadata(file.Name())
+
+	switch parsed.Type {
+	case parse.TypeEpisode:
+		return &LocalEpisode{
+			FileInfo: file,
+			Metadata: meta,
+			path:     path,
+			tvshow:   parsed.Name,
+			season:   parsed.Season,
+			episode:  parsed.Episode,
+			episodes: parsed.Episodes,
+		}, nil
+	case parse.TypeMovie:
+		return &LocalMovie{
+			FileInfo: file,
+			Metadata: meta,
+			path:     path,
+			movie:    parsed.Name,
+			year:     parsed.Year,
+		}, nil
+	default:
+		return nil, fmt.Errorf("could not parse media: %s", file.Name())
+	}
+}
+
+// LocalMovie is a movie found on the local filesystem, parsed from its
+// filename via parse.Filename.
+type LocalMovie struct {
+	os.FileInfo
+	Metadata
+	path   string
+	movie  string
+	year   int
+	imdbID string
+	tmdbID string
+}
+
+func (m *LocalMovie) Path() string {
+	return m.path
+}
+
+func (m *LocalMovie) Open() (io.ReadCloser, error) {
+	return os.Open(m.path)
+}
+
+func (m *LocalMovie) MovieName() string {
+	return m.movie
+}
+
+func (m *LocalMovie) Year() int {
+	return m.year
+}
+
+// IMDbID returns the IMDb identifier scraped for the movie, or "" if the
+// movie hasn't been merged with a scraped result that has one.
+func (m *LocalMovie) IMDbID() string {
+	return m.imdbID
+}
+
+// TMDbID returns the TMDb identifier scraped for the movie, or "" if the
+// movie hasn't been merged with a scraped result that has one.
+func (m *LocalMovie) TMDbID() string {
+	return m.tmdbID
+}
+
+func (m *LocalMovie) Meta() types.Metadata {
+	return m.Metadata
+}
+
+func (m *LocalMovie) TypeMovie() (types.Movie, bool) {
+	return m, true
+}
+
+func (m *LocalMovie) TypeEpisode() (types.Episode, bool) {
+	return nil, false
+}
+
+// Merge updates the movie with the (presumably more accurate) fields of a
+// scraped movie.
+func (m *LocalMovie) Merge(other types.Media) error {
+	movie, ok := other.(types.Movie)
+	if !ok {
+		return fmt.Errorf("cannot merge movie with %T", other)
+	}
+	m.movie = movie.MovieName()
+	m.year = movie.Year()
+	if i, ok := other.(imdbIDer); ok {
+		m.imdbID = i.IMDbID()
+	}
+	if t, ok := other.(tmdbIDer); ok {
+		m.tmdbID = t.TMDbID()
+	}
+	return nil
+}
+
+// LocalEpisode is a TV episode found on the local filesystem, parsed from
+// its filename via parse.Filename.
+type LocalEpisode struct {
+	os.FileInfo
+	Metadata
+	path     string
+	tvshow   string
+	name     string
+	season   int
+	episode  int
+	episodes []int
+	imdbID   string
+	tmdbID   string
+}
+
+func (e *LocalEpisode) Path() string {
+	return e.path
+}
+
+func (e *LocalEpisode) Open() (io.ReadCloser, error) {
+	return os.Open(e.path)
+}
+
+func (e *LocalEpisode) TVShow() string {
+	return e.tvshow
+}
+
+func (e *LocalEpisode) EpisodeName() string {
+	return e.name
+}
+
+func (e *LocalEpisode) Season() int {
+	return e.season
+}
+
+func (e *LocalEpisode) Episode() int {
+	return e.episode
+}
+
+// Episodes returns every episode number covered by this release, e.g.
+// [2, 3] for a file parsed from "Name.S01E02E03", so renamers can produce
+// "S01E02-E03" style filenames instead of dropping all but the first one.
+func (e *LocalEpisode) Episodes() []int {
+	return e.episodes
+}
+
+// IMDbID returns the IMDb identifier scraped for the episode, or "" if the
+// episode hasn't been merged with a scraped result that has one.
+func (e *LocalEpisode) IMDbID() string {
+	return e.imdbID
+}
+
+// TMDbID returns the TMDb identifier scraped for the episode, or "" if the
+// episode hasn't been merged with a scraped result that has one.
+func (e *LocalEpisode) TMDbID() string {
+	return e.tmdbID
+}
+
+func (e *LocalEpisode) Meta() types.Metadata {
+	return e.Metadata
+}
+
+func (e *LocalEpisode) TypeMovie() (types.Movie, bool) {
+	return nil, false
+}
+
+func (e *LocalEpisode) TypeEpisode() (types.Episode, bool) {
+	return e, true
+}
+
+// Merge updates the episode with the (presumably more accurate) fields of a
+// scraped episode.
+func (e *LocalEpisode) Merge(other types.Media) error {
+	episode, ok := other.(types.Episode)
+	if !ok {
+		return fmt.Errorf("cannot merge episode with %T", other)
+	}
+	e.tvshow = episode.TVShow()
+	e.name = episode.EpisodeName()
+	e.season = episode.Season()
+	e.episode = episode.Episode()
+	if me, ok := other.(multiEpisoder); ok {
+		e.episodes = me.Episodes()
+	}
+	if i, ok := other.(imdbIDer); ok {
+		e.imdbID = i.IMDbID()
+	}
+	if t, ok := other.(tmdbIDer); ok {
+		e.tmdbID = t.TMDbID()
+	}
+	return nil
+}
+
+type multiEpisoder interface {
+	Episodes() []int
+}
+
+// imdbIDer is implemented by scraped media which expose an IMDb identifier,
+// e.g. a provider.Movie or provider.Episode.
+type imdbIDer interface {
+	IMDbID() string
+}
+
+// tmdbIDer is implemented by scraped media which expose a TMDb identifier.
+type tmdbIDer interface {
+	TMDbID() string
+}
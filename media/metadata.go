@@ -2,6 +2,7 @@ package media
 
 import (
 	"encoding/json"
+	"regexp"
 	"strings"
 
 	"github.com/tympanix/supper/meta/codec"
@@ -78,3 +79,23 @@ func (m Metadata) Source() source.Tag {
 func (m Metadata) AllTags() []string {
 	return m.tags
 }
+
+// IsCam reports whether the media was parsed as a cam release, i.e. a low
+// quality recording made in a cinema rather than a proper rip of the movie.
+func (m Metadata) IsCam() bool {
+	return m.quality == quality.QualityCam
+}
+
+var audioChannelsRegex = regexp.MustCompile(`\d\.\d`)
+
+// AudioChannels returns the audio channel layout of the media, e.g. "5.1",
+// as found among the release tags. It returns an empty string if no such
+// tag could be found.
+func (m Metadata) AudioChannels() string {
+	for _, tag := range m.tags {
+		if audioChannelsRegex.MatchString(tag) {
+			return audioChannelsRegex.FindString(tag)
+		}
+	}
+	return ""
+}
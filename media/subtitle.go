@@ -13,44 +13,127 @@ import (
 	"golang.org/x/text/language/display"
 )
 
+// subtitleExtensions are the file extensions recognized as subtitles.
+// Supper doesn't parse the contents of any of these containers (SubRip,
+// SubStation Alpha/Advanced SubStation Alpha, WebVTT, MicroDVD and the
+// VobSub ".sub"/".idx" pair) - it only renames and matches subtitle files
+// by filename, so there is nothing format-specific to do here beyond
+// recognizing the extension.
+var subtitleExtensions = map[string]struct{}{
+	".srt": {},
+	".ass": {},
+	".ssa": {},
+	".vtt": {},
+	".sub": {},
+	".idx": {},
+}
+
+// subtitleTags maps the release tags recognized in subtitle filenames, such
+// as "movie.en.hi.srt" or "movie.en.forced.srt", to the flags they set on
+// the parsed subtitle. "cc" and "sdh" are both treated as hearing-impaired
+// markers since they describe the same kind of subtitle track.
+var subtitleTags = map[string]struct {
+	hi     bool
+	forced bool
+	sdh    bool
+}{
+	"hi":     {hi: true},
+	"cc":     {hi: true},
+	"sdh":    {hi: true, sdh: true},
+	"forced": {forced: true},
+}
+
+// NewLocalSubtitle parses a local subtitle file from its filename. Besides
+// the plain "movie.en.srt" convention it also recognizes hearing-impaired,
+// forced and SDH markers as well as combinations of these, e.g.
+// "movie.en.hi.forced.srt".
 func NewLocalSubtitle(file os.FileInfo) (types.Subtitle, error) {
-	if filepath.Ext(file.Name()) != ".srt" {
+	ext := strings.ToLower(filepath.Ext(file.Name()))
+
+	if _, ok := subtitleExtensions[ext]; !ok {
 		return nil, errors.New("parsing non subtitle file as subtitle")
 	}
 
-	parts := strings.Split(file.Name(), ".")
-
-	if len(parts) < 2 {
-		return nil, errors.New("error parsing subtitle file")
+	name := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+	parts := strings.Split(name, ".")
+
+	var hi, forced, sdh bool
+	tag := language.Und
+
+	// A bare "Movie.srt" has no dot-separated language suffix at all, which
+	// is common when there's only one subtitle track; fall back to the
+	// undetermined language rather than treating the whole filename as a
+	// (necessarily invalid) language tag. The same applies to a filename
+	// like "Movie.forced.srt" or "Movie.hi.srt", where stripping the flag
+	// tags leaves nothing but the title itself at index 0 - parts[0] is
+	// always the title, never a language code, so it must not be parsed as
+	// one either.
+	if len(parts) > 1 {
+		i := len(parts) - 1
+		for i > 0 {
+			flags, ok := subtitleTags[strings.ToLower(parts[i])]
+			if !ok {
+				break
+			}
+			hi = hi || flags.hi
+			forced = forced || flags.forced
+			sdh = sdh || flags.sdh
+			i--
+		}
+		if i > 0 {
+			tag = language.Make(parts[i])
+		}
 	}
 
-	tag := language.Make(parts[len(parts)-2])
-
 	return &LocalSubtitle{
 		file,
 		tag,
+		hi,
+		forced,
+		sdh,
 	}, nil
 }
 
 type LocalSubtitle struct {
 	os.FileInfo
-	lang language.Tag
+	lang   language.Tag
+	hi     bool
+	forced bool
+	sdh    bool
 }
 
 func (l *LocalSubtitle) MarshalJSON() (b []byte, err error) {
 	return json.Marshal(struct {
-		File string       `json:"filename"`
-		Code language.Tag `json:"code"`
-		Lang string       `json:"language"`
+		File   string       `json:"filename"`
+		Code   language.Tag `json:"code"`
+		Lang   string       `json:"language"`
+		HI     bool         `json:"hi"`
+		Forced bool         `json:"forced"`
+		SDH    bool         `json:"sdh"`
 	}{
 		l.Name(),
 		l.Language(),
 		l.String(),
+		l.IsHI(),
+		l.IsForced(),
+		l.IsSDH(),
 	})
 }
 
 func (l *LocalSubtitle) IsHI() bool {
-	return false
+	return l.hi
+}
+
+// IsForced reports whether the subtitle only translates foreign dialogue
+// and on-screen text rather than the full audio track.
+func (l *LocalSubtitle) IsForced() bool {
+	return l.forced
+}
+
+// IsSDH reports whether the subtitle is intended for the deaf and hard of
+// hearing, describing non-dialogue audio cues in addition to dialogue.
+func (l *LocalSubtitle) IsSDH() bool {
+	return l.sdh
 }
 
 func (l *LocalSubtitle) Download() (io.ReadCloser, error) {
@@ -85,3 +168,32 @@ func (l *LocalSubtitle) TypeMovie() (types.Movie, bool) {
 func (l *LocalSubtitle) TypeEpisode() (types.Episode, bool) {
 	return nil, false
 }
+
+// forcedSubtitle and sdhSubtitle are implemented by concrete subtitles
+// which expose forced/SDH flags, such as *LocalSubtitle. IsForced and IsSDH
+// check for them via type assertion rather than requiring types.Subtitle
+// itself to declare them, so any types.Subtitle value - not just
+// *LocalSubtitle - can be queried generically, the same way app/app_rename.go
+// ducks types.Metadata for IsCam/AudioChannels.
+type forcedSubtitle interface {
+	IsForced() bool
+}
+
+type sdhSubtitle interface {
+	IsSDH() bool
+}
+
+// IsForced reports whether sub is a forced subtitle, i.e. one that only
+// translates foreign dialogue and on-screen text rather than the full
+// audio track. It returns false if sub doesn't expose the flag.
+func IsForced(sub types.Subtitle) bool {
+	f, ok := sub.(forcedSubtitle)
+	return ok && f.IsForced()
+}
+
+// IsSDH reports whether sub is intended for the deaf and hard of hearing.
+// It returns false if sub doesn't expose the flag.
+func IsSDH(sub types.Subtitle) bool {
+	s, ok := sub.(sdhSubtitle)
+	return ok && s.IsSDH()
+}
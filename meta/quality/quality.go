@@ -0,0 +1,35 @@
+package quality
+
+// Tag represents the quality of a media release, e.g. its resolution, or
+// that it is a cam/telesync recording rather than a proper rip.
+type Tag int
+
+// The quality tags recognized from a release string, ordered from lowest
+// to highest quality. QualityCam is deliberately the lowest since it
+// denotes an in-cinema recording rather than a digital source.
+const (
+	QualityUnknown Tag = iota
+	QualityCam
+	Quality480p
+	Quality720p
+	Quality1080p
+	Quality2160p
+)
+
+// String returns a human readable description of the quality tag.
+func (t Tag) String() string {
+	switch t {
+	case QualityCam:
+		return "CAM"
+	case Quality480p:
+		return "480p"
+	case Quality720p:
+		return "720p"
+	case Quality1080p:
+		return "1080p"
+	case Quality2160p:
+		return "2160p"
+	default:
+		return ""
+	}
+}
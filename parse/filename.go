@@ -0,0 +1,124 @@
+package parse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MediaType identifies which kind of media a parsed filename represents.
+type MediaType int
+
+const (
+	// TypeUnknown is returned when no pattern matched the filename.
+	TypeUnknown MediaType = iota
+	// TypeMovie identifies a movie release.
+	TypeMovie
+	// TypeEpisode identifies a TV episode release.
+	TypeEpisode
+)
+
+// Media is the result of parsing a release filename with Filename. Depending
+// on Type, either the movie fields (Name, Year) or the episode fields
+// (Name, Season, Episode, Episodes) are meaningful.
+type Media struct {
+	Type     MediaType
+	Name     string
+	Year     int
+	Season   int
+	Episode  int
+	Episodes []int
+}
+
+type filenamePattern struct {
+	kind  MediaType
+	regex *regexp.Regexp
+}
+
+// filenamePatterns are tried in order, the first to match wins. Episode
+// patterns are ordered from most to least specific so that, for example, a
+// multi-episode release isn't mistaken for a single one. The movie
+// "Name.YYYY" pattern runs before the bare absolute-episode-number pattern
+// because a movie's release year (e.g. "The.Matrix.1999") is itself a bare
+// 2-4 digit run and would otherwise be misread as an absolute episode
+// number; the bare-number pattern is last and only matches once nothing
+// more specific, including a year, has.
+var filenamePatterns = []filenamePattern{
+	{TypeEpisode, regexp.MustCompile(`(?i)^(?P<name>.+?)[\s._-]+[Ss](?P<season>\d{1,2})(?P<episodes>(?:[Ee]\d{1,3})+)(?:[\s._-]|$)`)},
+	{TypeEpisode, regexp.MustCompile(`(?i)^(?P<name>.+?)[\s._-]+(?P<season>\d{1,2})x(?P<episode>\d{1,3})(?:[\s._-]|$)`)},
+	{TypeEpisode, regexp.MustCompile(`(?i)^(?P<name>.+?)[\s._-]+[Pp]art[\s._-]*(?P<episode>\d{1,3})(?:[\s._-]|$)`)},
+	{TypeEpisode, regexp.MustCompile(`(?i)^(?P<name>.+?)[\s._-]+[Ee](?P<episode>\d{1,3})(?:[\s._-]|$)`)},
+	{TypeMovie, regexp.MustCompile(`(?i)^(?P<name>.+?)[\s._-]+(?P<year>(?:19|20)\d{2})(?:[\s._-]|$)`)},
+	{TypeEpisode, regexp.MustCompile(`(?i)^(?P<name>.+?)[\s._-]+(?P<episode>\d{2,4})(?:[\s._-]|$)`)},
+}
+
+var episodeTagRegex = regexp.MustCompile(`(?i)[Ee](\d{1,3})`)
+var nameSeparatorRegex = regexp.MustCompile(`[._]+`)
+var trailingSeparatorRegex = regexp.MustCompile(`[\s._-]+$`)
+
+// Filename parses a release filename, stripped of its extension, into a
+// discriminated Media value. It recognizes "Name.S01E02", multi-episode
+// "Name.S01E02E03", "Name.1x02"/"Name.01x02", bare "Name.E02", anthology
+// "Name.Part.4"/"Name.Part11", absolute-numbered anime such as
+// "Name.123" and plain "Name.YYYY" movies. Patterns are tried in the order
+// of filenamePatterns: the movie pattern comes after every episode pattern
+// except the bare absolute number, and a name matching none of them is
+// returned as TypeUnknown with its cleaned name.
+func Filename(name string) Media {
+	for _, p := range filenamePatterns {
+		match := p.regex.FindStringSubmatch(name)
+		if match == nil {
+			continue
+		}
+
+		groups := make(map[string]string)
+		for i, g := range p.regex.SubexpNames() {
+			if i == 0 || g == "" {
+				continue
+			}
+			groups[g] = match[i]
+		}
+
+		media := Media{
+			Type: p.kind,
+			Name: cleanName(groups["name"]),
+		}
+
+		switch p.kind {
+		case TypeMovie:
+			media.Year, _ = strconv.Atoi(groups["year"])
+		case TypeEpisode:
+			if season, ok := groups["season"]; ok && season != "" {
+				media.Season, _ = strconv.Atoi(season)
+			} else {
+				media.Season = 1
+			}
+			if episodes, ok := groups["episodes"]; ok && episodes != "" {
+				for _, m := range episodeTagRegex.FindAllStringSubmatch(episodes, -1) {
+					ep, _ := strconv.Atoi(m[1])
+					media.Episodes = append(media.Episodes, ep)
+				}
+				media.Episode = media.Episodes[0]
+			} else {
+				media.Episode, _ = strconv.Atoi(groups["episode"])
+				media.Episodes = []int{media.Episode}
+			}
+		}
+
+		return media
+	}
+
+	return Media{
+		Type: TypeUnknown,
+		Name: cleanName(name),
+	}
+}
+
+// cleanName normalizes the name extracted from a release filename,
+// replacing dots and underscores with spaces and trimming any leftover
+// separators.
+func cleanName(name string) string {
+	name = nameSeparatorRegex.ReplaceAllString(name, " ")
+	name = trailingSeparatorRegex.ReplaceAllString(name, "")
+	return strings.TrimSpace(name)
+}
@@ -0,0 +1,69 @@
+package parse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want Media
+	}{
+		{
+			name: "single episode",
+			in:   "Show.Name.S01E02.720p.HDTV.x264-GROUP",
+			want: Media{Type: TypeEpisode, Name: "Show Name", Season: 1, Episode: 2, Episodes: []int{2}},
+		},
+		{
+			name: "multi episode",
+			in:   "Show.Name.S01E02E03.720p.HDTV.x264-GROUP",
+			want: Media{Type: TypeEpisode, Name: "Show Name", Season: 1, Episode: 2, Episodes: []int{2, 3}},
+		},
+		{
+			name: "alternate season episode separator",
+			in:   "Show.Name.1x02.720p.HDTV.x264-GROUP",
+			want: Media{Type: TypeEpisode, Name: "Show Name", Season: 1, Episode: 2, Episodes: []int{2}},
+		},
+		{
+			name: "anthology part",
+			in:   "Show.Name.Part.4.720p.HDTV.x264-GROUP",
+			want: Media{Type: TypeEpisode, Name: "Show Name", Season: 1, Episode: 4, Episodes: []int{4}},
+		},
+		{
+			name: "bare episode tag",
+			in:   "Show.Name.E02.720p.HDTV.x264-GROUP",
+			want: Media{Type: TypeEpisode, Name: "Show Name", Season: 1, Episode: 2, Episodes: []int{2}},
+		},
+		{
+			name: "movie with year",
+			in:   "The.Matrix.1999.1080p.BluRay.x264-GROUP",
+			want: Media{Type: TypeMovie, Name: "The Matrix", Year: 1999},
+		},
+		{
+			name: "movie year takes precedence over absolute episode numbering",
+			in:   "The.Matrix.1999.720p.HDTV.x264-GROUP",
+			want: Media{Type: TypeMovie, Name: "The Matrix", Year: 1999},
+		},
+		{
+			name: "absolute numbered anime",
+			in:   "Show.Name.123.720p.HDTV.x264-GROUP",
+			want: Media{Type: TypeEpisode, Name: "Show Name", Season: 1, Episode: 123, Episodes: []int{123}},
+		},
+		{
+			name: "unknown falls back to cleaned name",
+			in:   "Some.Random.File",
+			want: Media{Type: TypeUnknown, Name: "Some Random File"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Filename(tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filename(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package parse
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/tympanix/supper/meta/quality"
+)
+
+// camTags lists the release tokens which mark a cam or telesync recording,
+// i.e. a low quality capture made in a cinema rather than a proper rip of
+// the movie.
+var camTags = map[string]bool{
+	"cam":       true,
+	"camrip":    true,
+	"hdcam":     true,
+	"ts":        true,
+	"tsrip":     true,
+	"hdts":      true,
+	"telesync":  true,
+	"pdvd":      true,
+	"predvdrip": true,
+	"tc":        true,
+	"hdtc":      true,
+	"telecine":  true,
+	"wp":        true,
+	"workprint": true,
+}
+
+var wordRegex = regexp.MustCompile(`\w+`)
+
+// isCamRelease tokenizes release on non-word characters and reports
+// whether any of the resulting fields case-insensitively matches one of
+// the known cam/telesync release tags.
+func isCamRelease(release string) bool {
+	for _, field := range wordRegex.FindAllString(release, -1) {
+		if camTags[strings.ToLower(field)] {
+			return true
+		}
+	}
+	return false
+}
+
+var resolutionRegex = regexp.MustCompile(`(?i)(480|720|1080|2160)p`)
+
+// Quality parses the quality of a release from its tags, detecting its
+// resolution or, taking precedence over it, that it is a cam/telesync
+// recording.
+func Quality(tags string) quality.Tag {
+	if isCamRelease(tags) {
+		return quality.QualityCam
+	}
+
+	match := resolutionRegex.FindStringSubmatch(tags)
+	if match == nil {
+		return quality.QualityUnknown
+	}
+
+	switch match[1] {
+	case "480":
+		return quality.Quality480p
+	case "720":
+		return quality.Quality720p
+	case "1080":
+		return quality.Quality1080p
+	case "2160":
+		return quality.Quality2160p
+	default:
+		return quality.QualityUnknown
+	}
+}
@@ -0,0 +1,53 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/tympanix/supper/meta/quality"
+)
+
+func TestQuality(t *testing.T) {
+	tests := []struct {
+		name string
+		tags string
+		want quality.Tag
+	}{
+		{"480p", "Show.Name.S01E02.480p.HDTV.x264-GROUP", quality.Quality480p},
+		{"720p", "Show.Name.S01E02.720p.HDTV.x264-GROUP", quality.Quality720p},
+		{"1080p", "Show.Name.S01E02.1080p.BluRay.x264-GROUP", quality.Quality1080p},
+		{"2160p", "Show.Name.S01E02.2160p.UHD.BluRay.x264-GROUP", quality.Quality2160p},
+		{"unknown resolution", "Show.Name.S01E02.HDTV.x264-GROUP", quality.QualityUnknown},
+		{"cam", "Movie.Name.2020.CAM.x264-GROUP", quality.QualityCam},
+		{"telesync", "Movie.Name.2020.HDTS.x264-GROUP", quality.QualityCam},
+		{"cam takes precedence over resolution", "Movie.Name.2020.720p.CAM.x264-GROUP", quality.QualityCam},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Quality(tt.tags); got != tt.want {
+				t.Errorf("Quality(%q) = %v, want %v", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsCamRelease(t *testing.T) {
+	tests := []struct {
+		name    string
+		release string
+		want    bool
+	}{
+		{"cam tag", "Movie.Name.2020.CAM-GROUP", true},
+		{"workprint tag", "Movie.Name.2020.WORKPRINT-GROUP", true},
+		{"not a cam release", "Movie.Name.2020.1080p.BluRay.x264-GROUP", false},
+		{"camera is not a cam tag", "Movie.Name.2020.Camera.Shop-GROUP", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isCamRelease(tt.release); got != tt.want {
+				t.Errorf("isCamRelease(%q) = %v, want %v", tt.release, got, tt.want)
+			}
+		})
+	}
+}
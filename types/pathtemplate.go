@@ -0,0 +1,43 @@
+package types
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+)
+
+// PathTemplate is a path template used by the renamers to compute the
+// destination of a piece of media. It is satisfied by *text/template.Template
+// as well as any user-registered template sharing the same Execute
+// signature, so custom template implementations can be swapped in via
+// config.
+type PathTemplate interface {
+	Execute(wr io.Writer, data interface{}) error
+}
+
+// TemplateFuncs returns the template functions made available to path
+// templates on top of the text/template builtins. It lives here rather
+// than in the app package so that the config package, which is what
+// actually parses path templates, can register them without creating an
+// import cycle between config and app.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"pad": Pad,
+	}
+}
+
+// ParseTemplate parses text as a named path template with TemplateFuncs
+// registered, so functions such as {{pad 2 .Season}} resolve at execution
+// time. Funcs must be registered before Parse to be visible to it, so
+// whatever reads a user-supplied movies/tvshows template string (currently
+// the config package) should parse it through this rather than calling
+// text/template.New(...).Parse directly.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs()).Parse(text)
+}
+
+// Pad zero-pads v so it is at least width characters wide, e.g.
+// {{pad 2 5}} renders "05".
+func Pad(width int, v interface{}) string {
+	return fmt.Sprintf("%0*v", width, v)
+}
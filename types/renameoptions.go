@@ -0,0 +1,22 @@
+package types
+
+// RenameOptions controls how a single call to App.RenameMedia performs the
+// rename. Callers pass these explicitly rather than relying on global
+// config so that concurrent invocations (e.g. the API's per-request rename
+// jobs) can't race on one another's settings.
+type RenameOptions struct {
+	Action    string
+	Force     bool
+	DryRun    bool
+	RejectCam bool
+
+	// OnSkip, if set, is called once for each item App.RenameMedia leaves
+	// untouched instead of renaming (because it already exists and Force
+	// isn't set, or RejectCam rejected it as a cam release), with a short
+	// machine-readable reason ("exists" or "cam"). This lets callers such
+	// as the API's per-request rename jobs tell a skip apart from an
+	// actual rename without RenameMedia's own error return, which does not
+	// report skips unless the config is strict. With a strict config
+	// RenameMedia returns the skip as its error and OnSkip is not called.
+	OnSkip func(path, reason string)
+}